@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Chat is a single conversation: its message history and the
+// completion parameters it was configured with.
+type Chat struct {
+	ID       string
+	UserID   string
+	Messages []Message
+	Config   *ChatConfig
+}
+
+// NewChat starts a Chat for userID, seeded with initialSystemMessage.
+func NewChat(userID string, initialSystemMessage *Message, chatConfig *ChatConfig) (*Chat, error) {
+	chat := &Chat{
+		ID:       newChatID(),
+		UserID:   userID,
+		Messages: []Message{},
+		Config:   chatConfig,
+	}
+	if err := chat.AddMessage(initialSystemMessage); err != nil {
+		return nil, err
+	}
+	return chat, nil
+}
+
+// AddMessage appends msg to the chat's history.
+func (c *Chat) AddMessage(msg *Message) error {
+	c.Messages = append(c.Messages, *msg)
+	return nil
+}
+
+func newChatID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,14 @@
+package entity
+
+// ChatConfig holds the per-chat completion parameters and the Model
+// it's pinned to.
+type ChatConfig struct {
+	Temperature      float32
+	TopP             float32
+	N                int
+	Stop             []string
+	MaxTokens        int
+	PresencePenalty  float32
+	FrequencyPenalty float32
+	Model            *Model
+}
@@ -0,0 +1,46 @@
+package entity
+
+import "errors"
+
+// Message is one turn of a Chat's history. Model is the model it was
+// created against; Validate does not look at it. ToolCallID and
+// ToolCalls are only populated on the "tool" message carrying a
+// function result and the "assistant" message that requested it,
+// respectively.
+type Message struct {
+	Role       string
+	Content    string
+	Model      *Model
+	ToolCallID string
+	ToolCalls  []ToolCall
+}
+
+// NewMessage builds a Message for one of the standard roles, validating
+// it before returning. Messages outside that set (e.g. "tool" results
+// paired with ToolCallID) are constructed directly as Message values
+// instead, since they don't go through this validation.
+func NewMessage(role, content string, model *Model) (*Message, error) {
+	msg := &Message{
+		Role:    role,
+		Content: content,
+		Model:   model,
+	}
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// Validate enforces the constraints NewMessage relies on: a known role
+// and non-empty content.
+func (m *Message) Validate() error {
+	switch m.Role {
+	case "user", "system", "assistant":
+	default:
+		return errors.New("invalid role")
+	}
+	if m.Content == "" {
+		return errors.New("content is empty")
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package entity
+
+// Model identifies the LLM a chat is talking to and the size of its
+// context window, so ContextWindowManager knows how much headroom it
+// has to work with.
+type Model struct {
+	Name      string
+	MaxTokens int
+}
+
+// NewModel builds a Model for the given name, with maxTokens as the
+// size of its context window.
+func NewModel(name string, maxTokens int) *Model {
+	return &Model{Name: name, MaxTokens: maxTokens}
+}
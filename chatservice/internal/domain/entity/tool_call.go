@@ -0,0 +1,10 @@
+package entity
+
+// ToolCall is the entity-level record of a function call an assistant
+// message requested, carried on Message.ToolCalls so it can round-trip
+// through a provider's wire format and back.
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments string
+}
@@ -0,0 +1,69 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/entity"
+	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/tool"
+)
+
+// TokenUsage reports token accounting for a completion, when the
+// provider makes it available (e.g. Anthropic returns it on the final
+// stream event).
+type TokenUsage struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+// ToolCall is a fully-buffered function call request from the model:
+// providers accumulate the streamed name/argument fragments themselves
+// and emit one ToolCall per completed call.
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments string
+}
+
+// Delta is a single chunk of a streamed completion, normalized across
+// providers. Err is set when the underlying stream fails; consumers
+// should stop reading from the channel once it is non-nil. ToolCall is
+// set instead of Content when the model requests a function call.
+type Delta struct {
+	Content      string
+	FinishReason string
+	Usage        *TokenUsage
+	ToolCall     *ToolCall
+	Err          error
+}
+
+// LLMProviderRequest is the provider-agnostic shape of a chat completion
+// request. Each LLMProvider implementation is responsible for
+// translating Messages into its own wire format.
+type LLMProviderRequest struct {
+	Model            string
+	Messages         []entity.Message
+	MaxTokens        int
+	Temperature      float32
+	TopP             float32
+	PresencePenalty  float32
+	FrequencyPenalty float32
+	Stop             []string
+	// Tools are advertised to the model so it may request a function
+	// call instead of (or before) an assistant message. Only the
+	// OpenAI-compatible providers (OpenAI, Azure) send them; the
+	// Anthropic and Cohere providers leave them out of the request.
+	Tools []tool.ToolSpec
+}
+
+// LLMProvider is implemented by each backend (OpenAI, Azure OpenAI,
+// Cohere, Anthropic, ...) that ChatCompletionUseCase can stream a
+// completion from.
+type LLMProvider interface {
+	StreamCompletion(ctx context.Context, req LLMProviderRequest) (<-chan Delta, error)
+}
+
+// LLMProviderFactory resolves a provider by name, optionally overriding
+// its base URL (e.g. for self-hosted or proxied deployments). An empty
+// name selects the default provider.
+type LLMProviderFactory func(provider, baseURL string) (LLMProvider, error)
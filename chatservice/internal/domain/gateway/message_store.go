@@ -0,0 +1,17 @@
+package gateway
+
+import "context"
+
+// MessageStore lets a chat completion persist an assistant message's
+// content incrementally, as deltas arrive, instead of only once the
+// full answer is done. This is what keeps a process crash mid-stream
+// from losing everything that was already generated.
+type MessageStore interface {
+	AppendDelta(ctx context.Context, chatID, messageID, delta string) error
+	// Finalize reconciles the incrementally-appended rows for messageID
+	// with the finished content once SaveChat has persisted it, e.g. by
+	// replacing them with a single row or marking them complete. It is
+	// the link between the partial trail AppendDelta leaves behind and
+	// the message the chat gateway ends up considering authoritative.
+	Finalize(ctx context.Context, chatID, messageID, content string) error
+}
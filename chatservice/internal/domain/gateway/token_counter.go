@@ -0,0 +1,15 @@
+package gateway
+
+import "github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/entity"
+
+// TokenCounter estimates how many tokens a message will cost against a
+// model's context window. Tokenization differs across vendors, so each
+// provider family gets its own implementation.
+type TokenCounter interface {
+	Count(model string, msg entity.Message) int
+}
+
+// TokenCounterFactory resolves a TokenCounter by provider name,
+// mirroring LLMProviderFactory. An empty name selects the default
+// counter.
+type TokenCounterFactory func(provider string) (TokenCounter, error)
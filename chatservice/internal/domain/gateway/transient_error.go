@@ -0,0 +1,21 @@
+package gateway
+
+import "errors"
+
+// TransientStreamError marks a stream failure as safe to retry (rate
+// limiting, a server error, a dropped connection) as opposed to one
+// that will just fail again if resubmitted.
+type TransientStreamError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *TransientStreamError) Error() string { return e.Err.Error() }
+func (e *TransientStreamError) Unwrap() error { return e.Err }
+
+// IsTransientStreamError reports whether err (or something it wraps) is
+// a TransientStreamError.
+func IsTransientStreamError(err error) bool {
+	var transient *TransientStreamError
+	return errors.As(err, &transient)
+}
@@ -0,0 +1,49 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// NewDirTreeTool lets the model inspect a directory structure, e.g. to
+// orient itself in a project before answering a question about it.
+func NewDirTreeTool() ToolSpec {
+	return ToolSpec{
+		Name:        "dir_tree",
+		Description: "Lists files and directories under the given path, recursively.",
+		Parameters: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"path": {"type": "string", "description": "Root path to walk"}
+			},
+			"required": ["path"]
+		}`),
+		Impl: dirTreeImpl,
+	}
+}
+
+func dirTreeImpl(ctx context.Context, arguments string) (string, error) {
+	var args struct {
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return "", errors.New("error decoding dir_tree arguments: " + err.Error())
+	}
+
+	var out strings.Builder
+	err := filepath.WalkDir(args.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		out.WriteString(path + "\n")
+		return nil
+	})
+	if err != nil {
+		return "", errors.New("error walking dir_tree path: " + err.Error())
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,51 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// NewHTTPGetTool lets the model fetch a URL and read its response body
+// back into the conversation.
+func NewHTTPGetTool() ToolSpec {
+	return ToolSpec{
+		Name:        "http_get",
+		Description: "Performs an HTTP GET request and returns the response body as text.",
+		Parameters: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"url": {"type": "string", "description": "URL to fetch"}
+			},
+			"required": ["url"]
+		}`),
+		Impl: httpGetImpl,
+	}
+}
+
+func httpGetImpl(ctx context.Context, arguments string) (string, error) {
+	var args struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return "", errors.New("error decoding http_get arguments: " + err.Error())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, args.URL, nil)
+	if err != nil {
+		return "", errors.New("error building http_get request: " + err.Error())
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", errors.New("error calling http_get: " + err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.New("error reading http_get response: " + err.Error())
+	}
+	return string(body), nil
+}
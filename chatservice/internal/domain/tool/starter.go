@@ -0,0 +1,11 @@
+package tool
+
+// NewStarterToolbox returns a Toolbox pre-registered with a small set
+// of general-purpose tools (dir_tree, http_get) to bootstrap agent-style
+// chats on top of ChatCompletionUseCase.
+func NewStarterToolbox() *Toolbox {
+	toolbox := NewToolbox()
+	toolbox.Register(NewDirTreeTool())
+	toolbox.Register(NewHTTPGetTool())
+	return toolbox
+}
@@ -0,0 +1,64 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// ToolSpec describes a function the model can call mid-completion.
+// Parameters is a JSON schema for the arguments object; Impl receives
+// the model's raw (already-buffered) arguments JSON and returns the
+// tool's result as text to feed back into the chat.
+type ToolSpec struct {
+	Name        string
+	Description string
+	Parameters  json.RawMessage
+	Impl        func(ctx context.Context, arguments string) (string, error)
+}
+
+// Toolbox is a registry of ToolSpecs keyed by name, safe for concurrent
+// use across chat completions.
+type Toolbox struct {
+	mu    sync.RWMutex
+	tools map[string]ToolSpec
+}
+
+func NewToolbox() *Toolbox {
+	return &Toolbox{tools: make(map[string]ToolSpec)}
+}
+
+func (t *Toolbox) Register(spec ToolSpec) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tools[spec.Name] = spec
+}
+
+func (t *Toolbox) Get(name string) (ToolSpec, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	spec, ok := t.tools[name]
+	return spec, ok
+}
+
+// List returns the registered tools, e.g. to advertise them to an
+// LLMProvider on each request.
+func (t *Toolbox) List() []ToolSpec {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	specs := make([]ToolSpec, 0, len(t.tools))
+	for _, spec := range t.tools {
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// Call invokes the named tool with its raw arguments JSON.
+func (t *Toolbox) Call(ctx context.Context, name, arguments string) (string, error) {
+	spec, ok := t.Get(name)
+	if !ok {
+		return "", fmt.Errorf("unknown tool: %s", name)
+	}
+	return spec.Impl(ctx, arguments)
+}
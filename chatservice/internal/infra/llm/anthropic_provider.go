@@ -0,0 +1,153 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/gateway"
+)
+
+const (
+	defaultAnthropicBaseURL = "https://api.anthropic.com/v1"
+	anthropicAPIVersion     = "2023-06-01"
+)
+
+// AnthropicProvider streams completions from the Anthropic Messages API.
+type AnthropicProvider struct {
+	apiKey  string
+	baseURL string
+}
+
+// NewAnthropicProvider builds an AnthropicProvider reading its API key
+// from ANTHROPIC_API_KEY. An empty baseURL uses Anthropic's public API.
+func NewAnthropicProvider(baseURL string) *AnthropicProvider {
+	if baseURL == "" {
+		baseURL = defaultAnthropicBaseURL
+	}
+	return &AnthropicProvider{apiKey: os.Getenv("ANTHROPIC_API_KEY"), baseURL: baseURL}
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	System    string             `json:"system,omitempty"`
+	Messages  []anthropicMessage `json:"messages"`
+	MaxTokens int                `json:"max_tokens"`
+	// Temperature and TopP are sent unconditionally (no omitempty): a
+	// caller asking for 0 wants deterministic output, not Anthropic's
+	// default.
+	Temperature   float32  `json:"temperature"`
+	TopP          float32  `json:"top_p"`
+	StopSequences []string `json:"stop_sequences,omitempty"`
+	Stream        bool     `json:"stream"`
+}
+
+// anthropicStreamEvent covers the fields used across the
+// content_block_delta, message_delta and message_stop SSE events; the
+// Anthropic streaming protocol reuses one envelope with optional parts.
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Text       string `json:"text"`
+		StopReason string `json:"stop_reason"`
+	} `json:"delta"`
+	// Message carries the input_tokens usage reported on message_start;
+	// message_delta only ever reports output_tokens.
+	Message struct {
+		Usage struct {
+			InputTokens int `json:"input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+func (p *AnthropicProvider) StreamCompletion(ctx context.Context, req gateway.LLMProviderRequest) (<-chan gateway.Delta, error) {
+	anthropicReq := toAnthropicRequest(req)
+	body, err := json.Marshal(anthropicReq)
+	if err != nil {
+		return nil, errors.New("error encoding anthropic request: " + err.Error())
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.New("error building anthropic request: " + err.Error())
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", p.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicAPIVersion)
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, classifyStreamErr(fmt.Errorf("error calling anthropic: %w", err))
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, classifyHTTPStatus(resp.StatusCode, errors.New("anthropic returned non-200 status: "+resp.Status))
+	}
+
+	deltas := make(chan gateway.Delta)
+	go func() {
+		defer close(deltas)
+		defer resp.Body.Close()
+		usage := &gateway.TokenUsage{}
+		err := readSSELines(resp.Body, func(data []byte) (bool, error) {
+			var event anthropicStreamEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				return false, err
+			}
+			switch event.Type {
+			case "message_start":
+				usage.PromptTokens = event.Message.Usage.InputTokens
+			case "content_block_delta":
+				deltas <- gateway.Delta{Content: event.Delta.Text}
+			case "message_delta":
+				usage.CompletionTokens = event.Usage.OutputTokens
+				usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+				if event.Delta.StopReason != "" {
+					deltas <- gateway.Delta{FinishReason: event.Delta.StopReason, Usage: usage}
+				}
+			case "message_stop":
+				return true, nil
+			}
+			return false, nil
+		})
+		if err != nil {
+			deltas <- gateway.Delta{Err: classifyStreamErr(err)}
+		}
+	}()
+	return deltas, nil
+}
+
+func toAnthropicRequest(req gateway.LLMProviderRequest) anthropicRequest {
+	var system string
+	messages := make([]anthropicMessage, 0, len(req.Messages))
+	for _, msg := range req.Messages {
+		if msg.Role == "system" {
+			system = msg.Content
+			continue
+		}
+		messages = append(messages, anthropicMessage{Role: msg.Role, Content: msg.Content})
+	}
+	return anthropicRequest{
+		Model:         req.Model,
+		System:        system,
+		Messages:      messages,
+		MaxTokens:     req.MaxTokens,
+		Temperature:   req.Temperature,
+		TopP:          req.TopP,
+		StopSequences: req.Stop,
+		Stream:        true,
+	}
+}
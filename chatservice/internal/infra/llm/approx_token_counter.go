@@ -0,0 +1,20 @@
+package llm
+
+import "github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/entity"
+
+// ApproxTokenCounter estimates tokens for providers without a public Go
+// tokenizer (Cohere, Anthropic), using the common ~4-characters-per-token
+// heuristic.
+type ApproxTokenCounter struct{}
+
+func NewApproxTokenCounter() *ApproxTokenCounter {
+	return &ApproxTokenCounter{}
+}
+
+func (c *ApproxTokenCounter) Count(model string, msg entity.Message) int {
+	return approxTokenCount(msg.Content)
+}
+
+func approxTokenCount(content string) int {
+	return len(content)/4 + 1
+}
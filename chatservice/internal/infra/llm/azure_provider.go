@@ -0,0 +1,28 @@
+package llm
+
+import (
+	"context"
+	"os"
+
+	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/gateway"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// AzureProvider streams completions from an Azure OpenAI deployment.
+// It reuses go-openai's chat completions support, which is wire
+// compatible with OpenAI once pointed at the Azure config.
+type AzureProvider struct {
+	client *openai.Client
+}
+
+// NewAzureProvider builds an AzureProvider reading its API key from
+// AZURE_OPENAI_API_KEY. baseURL must be the deployment's resource
+// endpoint (e.g. https://my-resource.openai.azure.com).
+func NewAzureProvider(baseURL string) *AzureProvider {
+	config := openai.DefaultAzureConfig(os.Getenv("AZURE_OPENAI_API_KEY"), baseURL)
+	return &AzureProvider{client: openai.NewClientWithConfig(config)}
+}
+
+func (p *AzureProvider) StreamCompletion(ctx context.Context, req gateway.LLMProviderRequest) (<-chan gateway.Delta, error) {
+	return streamFromClient(ctx, p.client, req)
+}
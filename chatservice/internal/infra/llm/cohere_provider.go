@@ -0,0 +1,157 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/gateway"
+)
+
+const defaultCohereBaseURL = "https://api.cohere.ai/v1"
+
+// CohereProvider streams completions from Cohere's chat API.
+type CohereProvider struct {
+	apiKey  string
+	baseURL string
+}
+
+// NewCohereProvider builds a CohereProvider reading its API key from
+// COHERE_API_KEY. An empty baseURL uses Cohere's public API.
+func NewCohereProvider(baseURL string) *CohereProvider {
+	if baseURL == "" {
+		baseURL = defaultCohereBaseURL
+	}
+	return &CohereProvider{apiKey: os.Getenv("COHERE_API_KEY"), baseURL: baseURL}
+}
+
+type cohereChatMessage struct {
+	Role    string `json:"role"`
+	Message string `json:"message"`
+}
+
+type cohereChatRequest struct {
+	Model       string              `json:"model"`
+	Message     string              `json:"message"`
+	ChatHistory []cohereChatMessage `json:"chat_history"`
+	// Temperature and P are sent unconditionally (no omitempty): a
+	// caller asking for 0 wants deterministic output, not Cohere's
+	// default.
+	Temperature   float32  `json:"temperature"`
+	P             float32  `json:"p"`
+	MaxTokens     int      `json:"max_tokens,omitempty"`
+	StopSequences []string `json:"stop_sequences,omitempty"`
+	Stream        bool     `json:"stream"`
+}
+
+type cohereStreamEvent struct {
+	EventType    string `json:"event_type"`
+	Text         string `json:"text"`
+	FinishReason string `json:"finish_reason"`
+	Response     struct {
+		Meta struct {
+			BilledUnits struct {
+				InputTokens  int `json:"input_tokens"`
+				OutputTokens int `json:"output_tokens"`
+			} `json:"billed_units"`
+		} `json:"meta"`
+	} `json:"response"`
+}
+
+func (p *CohereProvider) StreamCompletion(ctx context.Context, req gateway.LLMProviderRequest) (<-chan gateway.Delta, error) {
+	chatReq := toCohereRequest(req)
+	body, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, errors.New("error encoding cohere request: " + err.Error())
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.New("error building cohere request: " + err.Error())
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, classifyStreamErr(fmt.Errorf("error calling cohere: %w", err))
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, classifyHTTPStatus(resp.StatusCode, errors.New("cohere returned non-200 status: "+resp.Status))
+	}
+
+	deltas := make(chan gateway.Delta)
+	go func() {
+		defer close(deltas)
+		defer resp.Body.Close()
+		// Cohere's v1 /chat stream is newline-delimited raw JSON, not
+		// SSE: no "data: " prefix, so it needs its own line scanner
+		// rather than readSSELines.
+		err := readJSONLines(resp.Body, func(data []byte) (bool, error) {
+			var event cohereStreamEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				return false, err
+			}
+			if event.EventType == "stream-end" {
+				deltas <- gateway.Delta{
+					FinishReason: event.FinishReason,
+					Usage: &gateway.TokenUsage{
+						PromptTokens:     event.Response.Meta.BilledUnits.InputTokens,
+						CompletionTokens: event.Response.Meta.BilledUnits.OutputTokens,
+						TotalTokens:      event.Response.Meta.BilledUnits.InputTokens + event.Response.Meta.BilledUnits.OutputTokens,
+					},
+				}
+				return true, nil
+			}
+			if event.EventType == "text-generation" {
+				deltas <- gateway.Delta{Content: event.Text}
+			}
+			return false, nil
+		})
+		if err != nil {
+			deltas <- gateway.Delta{Err: classifyStreamErr(err)}
+		}
+	}()
+	return deltas, nil
+}
+
+func toCohereRequest(req gateway.LLMProviderRequest) cohereChatRequest {
+	lastUserIndex := -1
+	for i, msg := range req.Messages {
+		if msg.Role == "user" {
+			lastUserIndex = i
+		}
+	}
+
+	history := make([]cohereChatMessage, 0, len(req.Messages))
+	lastUserMessage := ""
+	for i, msg := range req.Messages {
+		if i == lastUserIndex {
+			lastUserMessage = msg.Content
+			continue
+		}
+		role := "CHATBOT"
+		switch msg.Role {
+		case "user":
+			role = "USER"
+		case "system":
+			role = "SYSTEM"
+		}
+		history = append(history, cohereChatMessage{Role: role, Message: msg.Content})
+	}
+	return cohereChatRequest{
+		Model:         req.Model,
+		Message:       lastUserMessage,
+		ChatHistory:   history,
+		Temperature:   req.Temperature,
+		P:             req.TopP,
+		MaxTokens:     req.MaxTokens,
+		StopSequences: req.Stop,
+		Stream:        true,
+	}
+}
@@ -0,0 +1,41 @@
+package llm
+
+import (
+	"errors"
+	"io"
+	"net"
+
+	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/gateway"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// classifyStreamErr wraps err in a gateway.TransientStreamError when it
+// looks safe to retry: rate limiting, a server error, or a dropped
+// connection. Anything else is returned unchanged.
+func classifyStreamErr(err error) error {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.HTTPStatusCode == 429 || apiErr.HTTPStatusCode >= 500 {
+			return &gateway.TransientStreamError{StatusCode: apiErr.HTTPStatusCode, Err: err}
+		}
+		return err
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return &gateway.TransientStreamError{Err: err}
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return &gateway.TransientStreamError{Err: err}
+	}
+	return err
+}
+
+// classifyHTTPStatus wraps err in a gateway.TransientStreamError when
+// statusCode indicates a transient failure, for providers driven over
+// raw net/http rather than a go-openai stream.
+func classifyHTTPStatus(statusCode int, err error) error {
+	if statusCode == 429 || statusCode >= 500 {
+		return &gateway.TransientStreamError{StatusCode: statusCode, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,39 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/gateway"
+)
+
+// NewProvider resolves a gateway.LLMProvider by name, falling back to
+// OpenAI when provider is empty. It satisfies gateway.LLMProviderFactory.
+func NewProvider(provider, baseURL string) (gateway.LLMProvider, error) {
+	switch strings.ToLower(provider) {
+	case "", "openai":
+		return NewOpenAIProvider(baseURL), nil
+	case "azure":
+		return NewAzureProvider(baseURL), nil
+	case "cohere":
+		return NewCohereProvider(baseURL), nil
+	case "anthropic":
+		return NewAnthropicProvider(baseURL), nil
+	default:
+		return nil, fmt.Errorf("unsupported llm provider: %s", provider)
+	}
+}
+
+// NewTokenCounter resolves a gateway.TokenCounter by provider name,
+// falling back to OpenAI when provider is empty. It satisfies
+// gateway.TokenCounterFactory.
+func NewTokenCounter(provider string) (gateway.TokenCounter, error) {
+	switch strings.ToLower(provider) {
+	case "", "openai", "azure":
+		return NewTiktokenCounter(), nil
+	case "cohere", "anthropic":
+		return NewApproxTokenCounter(), nil
+	default:
+		return nil, fmt.Errorf("unsupported llm provider: %s", provider)
+	}
+}
@@ -0,0 +1,153 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/gateway"
+	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/tool"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// OpenAIProvider streams completions from the public OpenAI API.
+type OpenAIProvider struct {
+	client *openai.Client
+}
+
+// NewOpenAIProvider builds an OpenAIProvider reading its API key from
+// OPENAI_API_KEY. An empty baseURL uses go-openai's default.
+func NewOpenAIProvider(baseURL string) *OpenAIProvider {
+	config := openai.DefaultConfig(os.Getenv("OPENAI_API_KEY"))
+	if baseURL != "" {
+		config.BaseURL = baseURL
+	}
+	return &OpenAIProvider{client: openai.NewClientWithConfig(config)}
+}
+
+func (p *OpenAIProvider) StreamCompletion(ctx context.Context, req gateway.LLMProviderRequest) (<-chan gateway.Delta, error) {
+	return streamFromClient(ctx, p.client, req)
+}
+
+// streamFromClient drives a go-openai stream and normalizes it into
+// gateway.Delta values. It is shared by OpenAIProvider and
+// AzureProvider, which both talk to the OpenAI-compatible chat
+// completions API.
+func streamFromClient(ctx context.Context, client *openai.Client, req gateway.LLMProviderRequest) (<-chan gateway.Delta, error) {
+	messages := make([]openai.ChatCompletionMessage, 0, len(req.Messages))
+	for _, msg := range req.Messages {
+		message := openai.ChatCompletionMessage{
+			Role:       msg.Role,
+			Content:    msg.Content,
+			ToolCallID: msg.ToolCallID,
+		}
+		if len(msg.ToolCalls) > 0 {
+			toolCalls := make([]openai.ToolCall, 0, len(msg.ToolCalls))
+			for _, tc := range msg.ToolCalls {
+				toolCalls = append(toolCalls, openai.ToolCall{
+					ID:   tc.ID,
+					Type: openai.ToolTypeFunction,
+					Function: openai.FunctionCall{
+						Name:      tc.Name,
+						Arguments: tc.Arguments,
+					},
+				})
+			}
+			message.ToolCalls = toolCalls
+		}
+		messages = append(messages, message)
+	}
+
+	stream, err := client.CreateChatCompletionStream(
+		ctx,
+		openai.ChatCompletionRequest{
+			Model:            req.Model,
+			Messages:         messages,
+			MaxTokens:        req.MaxTokens,
+			Temperature:      req.Temperature,
+			TopP:             req.TopP,
+			PresencePenalty:  req.PresencePenalty,
+			FrequencyPenalty: req.FrequencyPenalty,
+			Stop:             req.Stop,
+			Tools:            toOpenAITools(req.Tools),
+			Stream:           true,
+		},
+	)
+	if err != nil {
+		return nil, classifyStreamErr(fmt.Errorf("error creating chat completion stream: %w", err))
+	}
+
+	deltas := make(chan gateway.Delta)
+	go func() {
+		defer close(deltas)
+		defer stream.Close()
+		pending := map[int]*gateway.ToolCall{}
+		for {
+			response, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				flushToolCalls(deltas, pending)
+				return
+			}
+			if err != nil {
+				deltas <- gateway.Delta{Err: classifyStreamErr(err)}
+				return
+			}
+			choice := response.Choices[0]
+			for _, tc := range choice.Delta.ToolCalls {
+				index := 0
+				if tc.Index != nil {
+					index = *tc.Index
+				}
+				call, ok := pending[index]
+				if !ok {
+					call = &gateway.ToolCall{}
+					pending[index] = call
+				}
+				if tc.ID != "" {
+					call.ID = tc.ID
+				}
+				if tc.Function.Name != "" {
+					call.Name = tc.Function.Name
+				}
+				call.Arguments += tc.Function.Arguments
+			}
+			if choice.Delta.Content != "" {
+				deltas <- gateway.Delta{Content: choice.Delta.Content}
+			}
+			if choice.FinishReason == openai.FinishReasonToolCalls {
+				flushToolCalls(deltas, pending)
+				return
+			}
+			if choice.FinishReason != "" {
+				deltas <- gateway.Delta{FinishReason: string(choice.FinishReason)}
+			}
+		}
+	}()
+	return deltas, nil
+}
+
+func flushToolCalls(deltas chan<- gateway.Delta, pending map[int]*gateway.ToolCall) {
+	for _, call := range pending {
+		deltas <- gateway.Delta{ToolCall: call}
+	}
+}
+
+func toOpenAITools(tools []tool.ToolSpec) []openai.Tool {
+	if len(tools) == 0 {
+		return nil
+	}
+	openaiTools := make([]openai.Tool, 0, len(tools))
+	for _, t := range tools {
+		openaiTools = append(openaiTools, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.Parameters,
+			},
+		})
+	}
+	return openaiTools
+}
@@ -0,0 +1,53 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// readSSELines scans a Server-Sent-Events body, invoking onData for the
+// payload of each "data: " line. It stops at io.EOF or the first read
+// error, and ignores keep-alive/comment lines.
+func readSSELines(body io.Reader, onData func(data []byte) (done bool, err error)) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if !bytes.HasPrefix(line, []byte("data: ")) {
+			continue
+		}
+		data := bytes.TrimPrefix(line, []byte("data: "))
+		done, err := onData(data)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// readJSONLines scans a body of newline-delimited raw JSON objects (one
+// event per line, with no SSE "data: " framing) invoking onData for
+// each non-blank line. It stops at io.EOF or the first read error, same
+// as readSSELines.
+func readJSONLines(body io.Reader, onData func(data []byte) (done bool, err error)) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		done, err := onData(line)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,30 @@
+package llm
+
+import (
+	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/entity"
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// perMessageOverhead approximates the extra tokens OpenAI's chat format
+// spends per message on role/name framing, per their token-counting
+// guidance.
+const perMessageOverhead = 4
+
+// TiktokenCounter counts tokens the way OpenAI and Azure OpenAI models
+// do, via the tiktoken-go port of OpenAI's own tokenizer.
+type TiktokenCounter struct{}
+
+func NewTiktokenCounter() *TiktokenCounter {
+	return &TiktokenCounter{}
+}
+
+func (c *TiktokenCounter) Count(model string, msg entity.Message) int {
+	enc, err := tiktoken.EncodingForModel(model)
+	if err != nil {
+		enc, err = tiktoken.GetEncoding("cl100k_base")
+		if err != nil {
+			return approxTokenCount(msg.Content)
+		}
+	}
+	return len(enc.Encode(msg.Content, nil, nil)) + perMessageOverhead
+}
@@ -0,0 +1,35 @@
+package ws
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// authenticate validates the JWT carried on the initial upgrade request
+// (as a "token" query parameter, since the browser WebSocket API cannot
+// set arbitrary headers) and returns the authenticated user ID.
+func (h *Handler) authenticate(r *http.Request) (string, error) {
+	tokenString := r.URL.Query().Get("token")
+	if tokenString == "" {
+		return "", errors.New("missing token")
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method: " + token.Method.Alg())
+		}
+		return h.JWTSecret, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	userID, _ := claims["user_id"].(string)
+	if userID == "" {
+		return "", errors.New("token missing user_id claim")
+	}
+	return userID, nil
+}
@@ -0,0 +1,148 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/gateway"
+	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/tool"
+	"github.com/cesaraugustomt/fullcycle/chatservice/internal/usecase/chatcompletionstream"
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// Handler upgrades HTTP requests to a WebSocket and streams a chat
+// completion over it, giving browsers a first-class alternative to the
+// existing gRPC/channel-only interface.
+type Handler struct {
+	ChatGateway          gateway.ChatGateway
+	LLMProviderFactory   gateway.LLMProviderFactory
+	Toolbox              *tool.Toolbox
+	ContextWindowManager *chatcompletionstream.ContextWindowManager
+	MessageStore         gateway.MessageStore
+	JWTSecret            []byte
+}
+
+// NewHandler builds a Handler reading its JWT signing secret from
+// WS_JWT_SECRET.
+func NewHandler(chatGateway gateway.ChatGateway, llmProviderFactory gateway.LLMProviderFactory, toolbox *tool.Toolbox, contextWindowManager *chatcompletionstream.ContextWindowManager, messageStore gateway.MessageStore) *Handler {
+	return &Handler{
+		ChatGateway:          chatGateway,
+		LLMProviderFactory:   llmProviderFactory,
+		Toolbox:              toolbox,
+		ContextWindowManager: contextWindowManager,
+		MessageStore:         messageStore,
+		JWTSecret:            []byte(os.Getenv("WS_JWT_SECRET")),
+	}
+}
+
+type inboundMessage struct {
+	ChatID      string                                            `json:"chat_id"`
+	UserMessage string                                            `json:"user_message"`
+	Config      chatcompletionstream.ChatCompletionConfigInputDTO `json:"config"`
+}
+
+type controlMessage struct {
+	Type string `json:"type"`
+}
+
+type outboundFrame struct {
+	ChatID       string                         `json:"chat_id"`
+	UserID       string                         `json:"user_id"`
+	MessageID    string                         `json:"message_id,omitempty"`
+	Event        chatcompletionstream.EventType `json:"event,omitempty"`
+	DeltaText    string                         `json:"delta_text,omitempty"`
+	ToolCall     *gateway.ToolCall              `json:"tool_call,omitempty"`
+	FinishReason string                         `json:"finish_reason,omitempty"`
+	Usage        *gateway.TokenUsage            `json:"usage,omitempty"`
+	Error        string                         `json:"error,omitempty"`
+	Done         bool                           `json:"done"`
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.authenticate(r)
+	if err != nil {
+		http.Error(w, "error authenticating request: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var msg inboundMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go h.watchForCancel(conn, cancel)
+
+	stream := make(chan chatcompletionstream.ChatCompletionOutputDTO)
+	useCase := chatcompletionstream.NewChatCompletionUseCase(h.ChatGateway, h.LLMProviderFactory, h.Toolbox, h.ContextWindowManager, h.MessageStore, stream)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := useCase.Execute(ctx, chatcompletionstream.ChatCompletionInputDTO{
+			ChatID:      msg.ChatID,
+			UserID:      userID,
+			UserMessage: msg.UserMessage,
+			Config:      msg.Config,
+		})
+		done <- err
+	}()
+
+	for {
+		select {
+		case evt := <-stream:
+			frame := outboundFrame{
+				ChatID:       evt.ChatID,
+				UserID:       evt.UserID,
+				MessageID:    evt.MessageID,
+				Event:        evt.Event,
+				DeltaText:    evt.DeltaText,
+				ToolCall:     evt.ToolCall,
+				FinishReason: evt.FinishReason,
+				Usage:        evt.Usage,
+			}
+			if evt.Err != nil {
+				frame.Error = evt.Err.Error()
+			}
+			if err := conn.WriteJSON(frame); err != nil {
+				cancel()
+			}
+		case err := <-done:
+			frame := outboundFrame{ChatID: msg.ChatID, UserID: userID, Done: true}
+			if err != nil {
+				frame.Error = err.Error()
+			}
+			conn.WriteJSON(frame)
+			return
+		}
+	}
+}
+
+// watchForCancel reads control messages off the socket (currently only
+// {"type":"cancel"} is meaningful) and cancels the in-flight completion
+// when one arrives, or when the socket itself is closed.
+func (h *Handler) watchForCancel(conn *websocket.Conn, cancel context.CancelFunc) {
+	defer cancel()
+	for {
+		var msg controlMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		if msg.Type == "cancel" {
+			return
+		}
+	}
+}
@@ -4,14 +4,18 @@ package chatcompletionstream
 import (
 	"context"
 	"errors"
-	"io"
 	"strings"
 
 	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/entity"
 	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/gateway"
-	openai "github.com/sashabaranov/go-openai"
+	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/tool"
 )
 
+// maxToolRounds caps how many completion rounds Execute runs (the first
+// request plus each re-issue after a tool call) before giving up, so a
+// model stuck requesting tools forever can't loop indefinitely.
+const maxToolRounds = 25
+
 type ChatCompletionConfigInputDTO struct {
 	Model                string
 	ModelMaxTokens       int
@@ -23,6 +27,19 @@ type ChatCompletionConfigInputDTO struct {
 	PresencePenalty      float32
 	FrequencyPenalty     float32
 	InitialSystemMessage string
+	// Provider selects the LLMProvider to stream from (e.g. "openai",
+	// "azure", "cohere", "anthropic"); empty defaults to "openai".
+	Provider string
+	// BaseURL overrides the provider's default API endpoint, e.g. for
+	// an Azure deployment or a self-hosted proxy.
+	BaseURL string
+	// TrimStrategy controls how the chat is brought back under
+	// ModelMaxTokens when it no longer fits; empty defaults to
+	// TrimOldest.
+	TrimStrategy TrimStrategy
+	// RetryPolicy controls reconnection after a transient stream
+	// error; the zero value applies sane defaults.
+	RetryPolicy RetryPolicy
 }
 
 type ChatCompletionInputDTO struct {
@@ -32,23 +49,41 @@ type ChatCompletionInputDTO struct {
 	Config      ChatCompletionConfigInputDTO
 }
 
+// ChatCompletionOutputDTO is the payload sent on Stream. Event
+// discriminates which of the fields below are meaningful; see the
+// EventXxx constants.
 type ChatCompletionOutputDTO struct {
-	ChatID  string
-	UserID  string
-	Content string
+	ChatID       string
+	UserID       string
+	MessageID    string
+	Event        EventType
+	DeltaText    string
+	ToolCall     *gateway.ToolCall
+	FinishReason string
+	Usage        *gateway.TokenUsage
+	Err          error
 }
 
 type ChatCompletionUseCase struct {
-	ChatGateway  gateway.ChatGateway
-	OpenAiClient *openai.Client
+	ChatGateway          gateway.ChatGateway
+	LLMProviderFactory   gateway.LLMProviderFactory
+	Toolbox              *tool.Toolbox
+	ContextWindowManager *ContextWindowManager
+	// MessageStore, when set, is written to incrementally as deltas
+	// arrive, so the assistant's partial answer survives a crash before
+	// SaveChat persists the finished message.
+	MessageStore gateway.MessageStore
 	Stream       chan ChatCompletionOutputDTO
 }
 
-func NewChatCompletionUseCase(chatGateway gateway.ChatGateway, openAiClient *openai.Client, steam chan ChatCompletionOutputDTO) *ChatCompletionUseCase {
+func NewChatCompletionUseCase(chatGateway gateway.ChatGateway, llmProviderFactory gateway.LLMProviderFactory, toolbox *tool.Toolbox, contextWindowManager *ContextWindowManager, messageStore gateway.MessageStore, steam chan ChatCompletionOutputDTO) *ChatCompletionUseCase {
 	return &ChatCompletionUseCase{
-		ChatGateway:  chatGateway,
-		OpenAiClient: openAiClient,
-		Stream:       steam,
+		ChatGateway:          chatGateway,
+		LLMProviderFactory:   llmProviderFactory,
+		Toolbox:              toolbox,
+		ContextWindowManager: contextWindowManager,
+		MessageStore:         messageStore,
+		Stream:               steam,
 	}
 }
 
@@ -78,50 +113,42 @@ func (uc *ChatCompletionUseCase) Execute(ctx context.Context, input ChatCompleti
 	if err != nil {
 		return nil, errors.New("error adding new message: " + err.Error())
 	}
-	messages := []openai.ChatCompletionMessage{}
-	for _, msg := range chat.Messages {
-		messages = append(messages, openai.ChatCompletionMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
-		})
+	if uc.ContextWindowManager != nil {
+		if err := uc.ContextWindowManager.Trim(ctx, chat, input.Config.Provider, input.Config.BaseURL, input.Config.TrimStrategy); err != nil {
+			return nil, errors.New("error trimming chat context: " + err.Error())
+		}
 	}
-	resp, err := uc.OpenAiClient.CreateChatCompletionStream(
-		ctx,
-		openai.ChatCompletionRequest{
-			Model:            chat.Config.Model.Name,
-			Messages:         messages,
-			MaxTokens:        chat.Config.MaxTokens,
-			Temperature:      chat.Config.Temperature,
-			TopP:             chat.Config.TopP,
-			PresencePenalty:  chat.Config.PresencePenalty,
-			FrequencyPenalty: chat.Config.FrequencyPenalty,
-			Stop:             chat.Config.Stop,
-			Stream:           true,
-		},
-	)
+	llmProvider, err := uc.LLMProviderFactory(input.Config.Provider, input.Config.BaseURL)
 	if err != nil {
-		return nil, errors.New("Error create chat completion: " + err.Error())
+		return nil, errors.New("error resolving llm provider: " + err.Error())
 	}
+	var tools []tool.ToolSpec
+	if uc.Toolbox != nil {
+		tools = uc.Toolbox.List()
+	}
+	retryPolicy := input.Config.RetryPolicy.withDefaults()
+	messageID := newMessageID()
+	uc.Stream <- ChatCompletionOutputDTO{ChatID: chat.ID, UserID: chat.UserID, MessageID: messageID, Event: EventStart}
 
-	var fullResponse strings.Builder
-
-	for {
-		response, err := resp.Recv()
-		if errors.Is(err, io.EOF) {
-			break
+	var content string
+	var finish gateway.Delta
+	for round := 0; ; round++ {
+		if round >= maxToolRounds {
+			err := errors.New("exceeded max tool-call rounds without a final answer")
+			uc.Stream <- ChatCompletionOutputDTO{ChatID: chat.ID, UserID: chat.UserID, MessageID: messageID, Event: EventError, Err: err}
+			return nil, err
 		}
+		var calledTool bool
+		content, finish, calledTool, err = uc.streamRound(ctx, chat, llmProvider, tools, retryPolicy, messageID)
 		if err != nil {
-			return nil, errors.New("Error stream response: " + err.Error())
+			uc.Stream <- ChatCompletionOutputDTO{ChatID: chat.ID, UserID: chat.UserID, MessageID: messageID, Event: EventError, Err: err}
+			return nil, err
 		}
-		fullResponse.WriteString(response.Choices[0].Delta.Content)
-		r := ChatCompletionOutputDTO{
-			ChatID:  chat.ID,
-			UserID:  chat.UserID,
-			Content: fullResponse.String(),
+		if !calledTool {
+			break
 		}
-		uc.Stream <- r
 	}
-	assistant, err := entity.NewMessage("assistant", fullResponse.String(), chat.Config.Model)
+	assistant, err := entity.NewMessage("assistant", content, chat.Config.Model)
 	if err != nil {
 		return nil, errors.New("Error creating assistant message: " + err.Error())
 	}
@@ -133,13 +160,167 @@ func (uc *ChatCompletionUseCase) Execute(ctx context.Context, input ChatCompleti
 	if err != nil {
 		return nil, errors.New("Error saving chat: " + err.Error())
 	}
+	if uc.MessageStore != nil {
+		if err := uc.MessageStore.Finalize(ctx, chat.ID, messageID, content); err != nil {
+			return nil, errors.New("error finalizing message store: " + err.Error())
+		}
+	}
+	uc.Stream <- ChatCompletionOutputDTO{
+		ChatID:       chat.ID,
+		UserID:       chat.UserID,
+		MessageID:    messageID,
+		Event:        EventFinish,
+		FinishReason: finish.FinishReason,
+		Usage:        finish.Usage,
+	}
 	return &ChatCompletionOutputDTO{
-		ChatID:  chat.ID,
-		UserID:  chat.UserID,
-		Content: fullResponse.String(),
+		ChatID:       chat.ID,
+		UserID:       chat.UserID,
+		MessageID:    messageID,
+		Event:        EventFinish,
+		FinishReason: finish.FinishReason,
+		Usage:        finish.Usage,
 	}, nil
 }
 
+// streamRound drives a single StreamCompletion call to completion,
+// transparently reconnecting on transient errors: it resubmits the
+// request with a synthetic assistant message holding the partial
+// answer already streamed, so the model continues instead of starting
+// over. It returns once the model finishes normally or requests a tool
+// call, or once the retry policy is exhausted.
+func (uc *ChatCompletionUseCase) streamRound(ctx context.Context, chat *entity.Chat, llmProvider gateway.LLMProvider, tools []tool.ToolSpec, retryPolicy RetryPolicy, messageID string) (string, gateway.Delta, bool, error) {
+	messages := chat.Messages
+	var fullResponse strings.Builder
+	attempt := 0
+
+	for {
+		deltas, err := llmProvider.StreamCompletion(ctx, gateway.LLMProviderRequest{
+			Model:            chat.Config.Model.Name,
+			Messages:         messages,
+			MaxTokens:        chat.Config.MaxTokens,
+			Temperature:      chat.Config.Temperature,
+			TopP:             chat.Config.TopP,
+			PresencePenalty:  chat.Config.PresencePenalty,
+			FrequencyPenalty: chat.Config.FrequencyPenalty,
+			Stop:             chat.Config.Stop,
+			Tools:            tools,
+		})
+		if err != nil {
+			if !gateway.IsTransientStreamError(err) || attempt+1 >= retryPolicy.MaxAttempts {
+				return "", gateway.Delta{}, false, errors.New("Error create chat completion: " + err.Error())
+			}
+			attempt++
+			if waitErr := retryPolicy.wait(ctx, attempt); waitErr != nil {
+				return "", gateway.Delta{}, false, waitErr
+			}
+			continue
+		}
+
+		calledTool := false
+		var finish gateway.Delta
+		var streamErr error
+		for delta := range deltas {
+			if delta.Err != nil {
+				streamErr = delta.Err
+				break
+			}
+			if delta.ToolCall != nil {
+				calledTool = true
+				uc.Stream <- ChatCompletionOutputDTO{
+					ChatID:    chat.ID,
+					UserID:    chat.UserID,
+					MessageID: messageID,
+					Event:     EventToolCall,
+					ToolCall:  delta.ToolCall,
+				}
+				if err := uc.callTool(ctx, chat, *delta.ToolCall, fullResponse.String()); err != nil {
+					return "", gateway.Delta{}, false, err
+				}
+				continue
+			}
+			if delta.FinishReason != "" {
+				finish = delta
+				continue
+			}
+			fullResponse.WriteString(delta.Content)
+			if uc.MessageStore != nil {
+				if err := uc.MessageStore.AppendDelta(ctx, chat.ID, messageID, delta.Content); err != nil {
+					return "", gateway.Delta{}, false, errors.New("error persisting delta: " + err.Error())
+				}
+			}
+			uc.Stream <- ChatCompletionOutputDTO{
+				ChatID:    chat.ID,
+				UserID:    chat.UserID,
+				MessageID: messageID,
+				Event:     EventDelta,
+				DeltaText: delta.Content,
+			}
+		}
+		if streamErr == nil {
+			return fullResponse.String(), finish, calledTool, nil
+		}
+		if !gateway.IsTransientStreamError(streamErr) || attempt+1 >= retryPolicy.MaxAttempts {
+			return "", gateway.Delta{}, false, errors.New("Error stream response: " + streamErr.Error())
+		}
+		attempt++
+		// Only splice in a synthetic assistant partial when something
+		// actually streamed before the error; an empty partial (e.g. an
+		// immediate 429) would fail entity.NewMessage's non-empty
+		// content check and turn a retryable error into a hard one, so
+		// just resubmit the original history instead.
+		if fullResponse.Len() > 0 {
+			partial, err := entity.NewMessage("assistant", fullResponse.String(), chat.Config.Model)
+			if err != nil {
+				return "", gateway.Delta{}, false, errors.New("error creating resume message: " + err.Error())
+			}
+			messages = append(append([]entity.Message{}, chat.Messages...), *partial)
+		}
+		if waitErr := retryPolicy.wait(ctx, attempt); waitErr != nil {
+			return "", gateway.Delta{}, false, waitErr
+		}
+	}
+}
+
+// callTool invokes the requested tool and appends the round trip OpenAI
+// expects to the chat: the assistant message that requested the call
+// (carrying ToolCalls, plus any content the model streamed before
+// deciding to call it), followed by the "tool" message holding its
+// result (carrying the matching ToolCallID). Both are built directly as
+// entity.Message values rather than through entity.NewMessage, since
+// NewMessage's role validation doesn't know about the "tool" role and
+// the assistant call message may have no content of its own to
+// validate.
+func (uc *ChatCompletionUseCase) callTool(ctx context.Context, chat *entity.Chat, call gateway.ToolCall, interimContent string) error {
+	if uc.Toolbox == nil {
+		return errors.New("error calling tool " + call.Name + ": no toolbox configured")
+	}
+	assistantCall := &entity.Message{
+		Role:    "assistant",
+		Content: interimContent,
+		ToolCalls: []entity.ToolCall{
+			{ID: call.ID, Name: call.Name, Arguments: call.Arguments},
+		},
+	}
+	if err := chat.AddMessage(assistantCall); err != nil {
+		return errors.New("error adding assistant tool-call message: " + err.Error())
+	}
+
+	result, err := uc.Toolbox.Call(ctx, call.Name, call.Arguments)
+	if err != nil {
+		result = "error calling tool " + call.Name + ": " + err.Error()
+	}
+	toolMessage := &entity.Message{
+		Role:       "tool",
+		Content:    result,
+		ToolCallID: call.ID,
+	}
+	if err := chat.AddMessage(toolMessage); err != nil {
+		return errors.New("error adding tool message: " + err.Error())
+	}
+	return nil
+}
+
 func createNewChat(input ChatCompletionInputDTO) (*entity.Chat, error) {
 	model := entity.NewModel(input.Config.Model, input.Config.ModelMaxTokens)
 	chatConfig := &entity.ChatConfig{
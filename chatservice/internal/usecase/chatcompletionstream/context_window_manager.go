@@ -0,0 +1,153 @@
+package chatcompletionstream
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/entity"
+	"github.com/cesaraugustomt/fullcycle/chatservice/internal/domain/gateway"
+)
+
+// TrimStrategy controls how ContextWindowManager reacts when a chat no
+// longer fits in its model's context window.
+type TrimStrategy string
+
+const (
+	// TrimOldest drops the oldest non-system messages until the chat
+	// fits. This is the default: it's lossy, but it keeps the chat
+	// going instead of failing mid-stream.
+	TrimOldest TrimStrategy = "trim_oldest"
+	// SummarizeOldest compresses dropped messages into a single
+	// system message via a secondary completion, instead of
+	// discarding them outright.
+	SummarizeOldest TrimStrategy = "summarize_oldest"
+	// Fail returns an error instead of trimming.
+	Fail TrimStrategy = "fail"
+)
+
+// summaryMarker prefixes the system message ContextWindowManager uses
+// to store the running summary, so it can find and update it across
+// calls instead of growing a new one each time.
+const summaryMarker = "[context summary] "
+
+// summaryMaxTokens bounds the secondary summarization completion.
+// Providers like Anthropic require max_tokens to be set and >0, and a
+// merged summary has no business running long anyway.
+const summaryMaxTokens = 256
+
+// ContextWindowManager keeps a chat's message history within its
+// model's token budget, counting tokens per entity.Message and
+// trimming or summarizing the oldest ones as needed.
+type ContextWindowManager struct {
+	CounterFactory     gateway.TokenCounterFactory
+	LLMProviderFactory gateway.LLMProviderFactory
+}
+
+func NewContextWindowManager(counterFactory gateway.TokenCounterFactory, llmProviderFactory gateway.LLMProviderFactory) *ContextWindowManager {
+	return &ContextWindowManager{
+		CounterFactory:     counterFactory,
+		LLMProviderFactory: llmProviderFactory,
+	}
+}
+
+// Trim mutates chat.Messages in place until their token count, plus the
+// chat's configured MaxTokens headroom for the reply, fits within
+// ModelMaxTokens. baseURL is forwarded to the SummarizeOldest strategy's
+// secondary completion, so it targets the same endpoint as the chat
+// itself (e.g. an Azure deployment or self-hosted proxy).
+func (m *ContextWindowManager) Trim(ctx context.Context, chat *entity.Chat, provider, baseURL string, strategy TrimStrategy) error {
+	counter, err := m.CounterFactory(provider)
+	if err != nil {
+		return errors.New("error resolving token counter: " + err.Error())
+	}
+	budget := chat.Config.Model.MaxTokens - chat.Config.MaxTokens
+
+	for m.countMessages(counter, chat) > budget {
+		index := firstDroppableIndex(chat.Messages)
+		if index == -1 {
+			return nil
+		}
+		switch strategy {
+		case SummarizeOldest:
+			if err := m.summarize(ctx, chat, provider, baseURL, index); err != nil {
+				return err
+			}
+		case Fail:
+			return errors.New("chat exceeds the model's max tokens and trim strategy is Fail")
+		default:
+			chat.Messages = append(chat.Messages[:index], chat.Messages[index+1:]...)
+		}
+	}
+	return nil
+}
+
+func (m *ContextWindowManager) countMessages(counter gateway.TokenCounter, chat *entity.Chat) int {
+	total := 0
+	for _, msg := range chat.Messages {
+		total += counter.Count(chat.Config.Model.Name, msg)
+	}
+	return total
+}
+
+// summarize folds the message at index into the chat's running summary
+// system message (creating one if it doesn't exist yet) via a secondary
+// completion, then removes the original message.
+func (m *ContextWindowManager) summarize(ctx context.Context, chat *entity.Chat, provider, baseURL string, index int) error {
+	dropped := chat.Messages[index]
+	chat.Messages = append(chat.Messages[:index], chat.Messages[index+1:]...)
+
+	existingSummary := ""
+	summaryIndex := -1
+	for i, msg := range chat.Messages {
+		if msg.Role == "system" && strings.HasPrefix(msg.Content, summaryMarker) {
+			existingSummary = strings.TrimPrefix(msg.Content, summaryMarker)
+			summaryIndex = i
+			break
+		}
+	}
+
+	llmProvider, err := m.LLMProviderFactory(provider, baseURL)
+	if err != nil {
+		return errors.New("error resolving llm provider for summarization: " + err.Error())
+	}
+	deltas, err := llmProvider.StreamCompletion(ctx, gateway.LLMProviderRequest{
+		Model:     chat.Config.Model.Name,
+		MaxTokens: summaryMaxTokens,
+		Messages: []entity.Message{
+			{Role: "system", Content: "Merge the new turn into the existing summary. Be concise and keep only what matters for future turns."},
+			{Role: "user", Content: "Existing summary:\n" + existingSummary + "\n\nNew turn (" + dropped.Role + "):\n" + dropped.Content},
+		},
+	})
+	if err != nil {
+		return errors.New("error requesting summary completion: " + err.Error())
+	}
+
+	var summary strings.Builder
+	for delta := range deltas {
+		if delta.Err != nil {
+			return errors.New("error streaming summary completion: " + delta.Err.Error())
+		}
+		summary.WriteString(delta.Content)
+	}
+
+	summaryMessage, err := entity.NewMessage("system", summaryMarker+summary.String(), chat.Config.Model)
+	if err != nil {
+		return errors.New("error creating summary message: " + err.Error())
+	}
+	if summaryIndex >= 0 {
+		chat.Messages[summaryIndex] = *summaryMessage
+	} else {
+		chat.Messages = append([]entity.Message{*summaryMessage}, chat.Messages...)
+	}
+	return nil
+}
+
+func firstDroppableIndex(messages []entity.Message) int {
+	for i, msg := range messages {
+		if msg.Role != "system" {
+			return i
+		}
+	}
+	return -1
+}
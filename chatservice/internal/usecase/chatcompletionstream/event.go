@@ -0,0 +1,39 @@
+package chatcompletionstream
+
+import "strings"
+
+// EventType discriminates the payload carried by a ChatCompletionOutputDTO
+// sent on Stream.
+type EventType string
+
+const (
+	// EventStart announces a new assistant turn, identified by MessageID,
+	// before anything has been streamed for it yet.
+	EventStart EventType = "start"
+	// EventDelta carries one incremental piece of assistant content in
+	// DeltaText. Unlike the full buffer sent before, consumers are
+	// expected to append it themselves.
+	EventDelta EventType = "delta"
+	// EventToolCall announces that the model asked to call ToolCall
+	// instead of producing more content.
+	EventToolCall EventType = "tool_call"
+	// EventFinish announces that the assistant turn is done, with
+	// FinishReason and, when the provider reports it, Usage.
+	EventFinish EventType = "finish"
+	// EventError announces that the turn ended in an unrecoverable
+	// error, carried in Err.
+	EventError EventType = "error"
+)
+
+// Collect drains ch and concatenates every EventDelta's DeltaText,
+// for callers that only want the final assistant answer and don't
+// care about the incremental events.
+func Collect(ch <-chan ChatCompletionOutputDTO) string {
+	var sb strings.Builder
+	for event := range ch {
+		if event.Event == EventDelta {
+			sb.WriteString(event.DeltaText)
+		}
+	}
+	return sb.String()
+}
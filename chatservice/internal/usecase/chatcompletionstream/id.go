@@ -0,0 +1,15 @@
+package chatcompletionstream
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newMessageID generates an identifier correlating the events emitted
+// on Stream for a single assistant turn, and the MessageStore calls
+// persisting its content as it streams in.
+func newMessageID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,61 @@
+package chatcompletionstream
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy controls how ChatCompletionUseCase reconnects after a
+// transient stream error instead of discarding the partial answer
+// already emitted on Stream.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+const (
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+)
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultMaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaultInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultMaxBackoff
+	}
+	return p
+}
+
+// backoff returns the delay before the given (1-indexed) retry attempt:
+// exponential growth capped at MaxBackoff, with up to 50% jitter so
+// concurrent retries don't all land on the provider at once.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.InitialBackoff << uint(attempt-1)
+	if delay <= 0 || delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay/2 + jitter
+}
+
+// wait sleeps for the attempt's backoff duration, returning early with
+// ctx's error if it's cancelled first.
+func (p RetryPolicy) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(p.backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return errors.New("context cancelled while waiting to retry: " + ctx.Err().Error())
+	case <-timer.C:
+		return nil
+	}
+}